@@ -1,10 +1,17 @@
 package main
 
 import (
+	"brewing-temperature-monitor-app/internal/alerts"
 	"brewing-temperature-monitor-app/internal/handlers"
 	"brewing-temperature-monitor-app/internal/helpers"
+	mqttingest "brewing-temperature-monitor-app/internal/ingest/mqtt"
+	"brewing-temperature-monitor-app/internal/scrapers"
+	"context"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
 
 	_ "brewing-temperature-monitor-app/docs"
 
@@ -43,8 +50,9 @@ func main() {
 		log.Fatal("INFLUX_TOKEN environment variable is not set")
 	}
 
-	// Set up InfluxDB connection
-	influxClient := influxdb2.NewClient("http://localhost:8086", token)
+	// Set up InfluxDB connection, with the async write batching tuned via
+	// WRITE_BATCH_SIZE / WRITE_FLUSH_INTERVAL_MS / WRITE_MAX_RETRIES
+	influxClient := influxdb2.NewClientWithOptions("http://localhost:8086", token, handlers.WriteOptionsFromEnv())
 	defer influxClient.Close()
 
 	// Retrieve InfluxDB organization and bucket from environment variables
@@ -57,9 +65,72 @@ func main() {
 	router := gin.Default()
 
 	recordHandler := handlers.NewRecordHandler(influxClient, org, bucket)
+
+	// Provision the tiered retention buckets and their downsample tasks
+	// before serving traffic so every bucket resolveBucket can pick for a
+	// query exists.
+	if err := recordHandler.EnsureBuckets(context.Background()); err != nil {
+		log.Fatalf("Failed to provision buckets: %v", err)
+	}
+	if err := recordHandler.RegisterDownsampleTasks(context.Background()); err != nil {
+		log.Fatalf("Failed to register downsample tasks: %v", err)
+	}
+
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 	handlers.RegisterRoutes(router, recordHandler)
 
+	// Run an MQTT bridge alongside the HTTP intake when MQTT_BROKER is set,
+	// forwarding subscribed readings through the same batch writer.
+	var mqttIngestor *mqttingest.Ingestor
+	if mqttConfig, ok := mqttingest.ConfigFromEnv(); ok {
+		mqttIngestor = mqttingest.NewIngestor(mqttConfig, recordHandler.WriteAPI)
+		if err := mqttIngestor.Start(); err != nil {
+			log.Fatalf("Failed to start MQTT ingestor: %v", err)
+		}
+		defer mqttIngestor.Stop()
+	}
+
+	// Load and start any alert rules, notifying over whichever notifiers are
+	// configured (webhook/SMTP/MQTT) when a rule fires or resolves.
+	var mqttPublisher alerts.Publisher
+	if mqttIngestor != nil {
+		mqttPublisher = mqttIngestor
+	}
+	alertManager := alerts.NewManager(recordHandler, alerts.NotifiersFromEnv(mqttPublisher)...)
+	if rulesFile := os.Getenv("ALERT_RULES_FILE"); rulesFile != "" {
+		rules, err := alerts.LoadRulesFromFile(rulesFile)
+		if err != nil {
+			log.Fatalf("Failed to load alert rules: %v", err)
+		}
+		for _, rule := range rules {
+			if err := alertManager.AddRule(rule); err != nil {
+				log.Fatalf("Failed to load alert rule %q: %v", rule.Name, err)
+			}
+		}
+	}
+	alerts.RegisterRoutes(router, alertManager)
+
+	// Run any configured pull-based scraper alongside the HTTP/MQTT intake,
+	// tagging its points with a "source" tag so they're distinguishable from
+	// device-submitted data.
+	scraperRegistry := scrapers.NewRegistry(recordHandler.WriteAPI)
+	if scraper, ok := scrapers.ScraperFromEnv(); ok {
+		scraperRegistry.Start(context.Background(), scraper)
+	}
+	scrapers.RegisterRoutes(router, scraperRegistry)
+
+	router.GET("/healthz", func(c *gin.Context) {
+		mqttStatus := "disabled"
+		if mqttIngestor != nil {
+			mqttStatus = "disconnected"
+			if mqttIngestor.Connected() {
+				mqttStatus = "connected"
+			}
+		}
+
+		c.JSON(http.StatusOK, gin.H{"status": "ok", "mqtt": mqttStatus})
+	})
+
 	go func() {
 		if err := router.Run(":8080"); err != nil {
 			log.Fatalf("Server failed to start: %v", err)
@@ -68,5 +139,10 @@ func main() {
 
 	go helpers.GenerateDummyData("http://localhost:8080/records")
 
-	select {}
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	<-quit
+
+	log.Println("Shutting down, draining write buffer...")
+	recordHandler.WriteAPI.Flush()
 }
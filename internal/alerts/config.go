@@ -0,0 +1,36 @@
+package alerts
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadRulesFromFile reads alert rule definitions from a YAML or JSON file,
+// the format chosen by its extension (.yaml/.yml or .json).
+func LoadRulesFromFile(path string) ([]Rule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file %q: %w", path, err)
+	}
+
+	var rules []Rule
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		if err := yaml.Unmarshal(data, &rules); err != nil {
+			return nil, fmt.Errorf("failed to parse YAML rules file %q: %w", path, err)
+		}
+	case ".json":
+		if err := json.Unmarshal(data, &rules); err != nil {
+			return nil, fmt.Errorf("failed to parse JSON rules file %q: %w", path, err)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported rules file extension %q", filepath.Ext(path))
+	}
+
+	return rules, nil
+}
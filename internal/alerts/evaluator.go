@@ -0,0 +1,83 @@
+package alerts
+
+import (
+	"brewing-temperature-monitor-app/internal/handlers"
+	"context"
+	"fmt"
+	"strings"
+)
+
+// fluxEvaluator evaluates a Rule by running a reducer over its window
+// through the shared RecordHandler, the same way the query handlers do.
+type fluxEvaluator struct {
+	handler *handlers.RecordHandler
+	rule    Rule
+}
+
+func (e *fluxEvaluator) Evaluate(ctx context.Context) (State, float64, error) {
+	result, err := handlers.ExecuteQuery(e.handler, e.buildQuery(), ctx)
+	if err != nil {
+		return StateOK, 0, err
+	}
+
+	var value float64
+	found := false
+	for result.Next() {
+		switch v := result.Record().Value().(type) {
+		case float64:
+			value = v
+			found = true
+		case int64: // count() yields integers
+			value = float64(v)
+			found = true
+		}
+	}
+	if err := result.Err(); err != nil {
+		return StateOK, 0, err
+	}
+	if !found {
+		return StateOK, 0, fmt.Errorf("rule %q: no data in window", e.rule.Name)
+	}
+
+	if breaches(e.rule.Op, value, e.rule.Threshold) {
+		return StateFiring, value, nil
+	}
+	return StateOK, value, nil
+}
+
+func (e *fluxEvaluator) buildQuery() string {
+	var filters strings.Builder
+	if e.rule.DeviceID != "" {
+		filters.WriteString(fmt.Sprintf(`
+			|> filter(fn: (r) => r["device_id"] == "%s")`, e.rule.DeviceID))
+	}
+	if e.rule.Location != "" {
+		filters.WriteString(fmt.Sprintf(`
+			|> filter(fn: (r) => r["location"] == "%s")`, e.rule.Location))
+	}
+
+	return fmt.Sprintf(`
+		from(bucket: "%s")
+			|> range(start: -%s)
+			|> filter(fn: (r) => r._measurement == "sensor_data")
+			|> filter(fn: (r) => r._field == "%s")%s
+			|> %s()
+	`, e.handler.Bucket, e.rule.Window.Duration(), e.rule.Field, filters.String(), reducerFn(e.rule.Reducer))
+}
+
+func reducerFn(reducer Reducer) string {
+	switch reducer {
+	case ReducerMean:
+		return "mean"
+	case ReducerMax:
+		return "max"
+	case ReducerMin:
+		return "min"
+	case ReducerLast:
+		return "last"
+	case ReducerCountNonNull:
+		return "count"
+	default:
+		return "mean"
+	}
+}
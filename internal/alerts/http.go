@@ -0,0 +1,97 @@
+package alerts
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes wires the alert rule CRUD and history endpoints onto
+// router.
+func RegisterRoutes(router *gin.Engine, manager *Manager) {
+	router.POST("/alerts/rules", createRuleHandler(manager))
+	router.GET("/alerts/rules", listRulesHandler(manager))
+	router.DELETE("/alerts/rules/:name", deleteRuleHandler(manager))
+	router.GET("/alerts/history", historyHandler(manager))
+}
+
+// createRuleHandler registers or replaces an alert rule.
+// @Summary Create or replace an alert rule
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param rule body Rule true "Alert rule definition"
+// @Success 201 {object} Rule
+// @Failure 400 {object} map[string]string "error: Invalid input"
+// @Router /alerts/rules [post]
+func createRuleHandler(manager *Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var rule Rule
+		if err := c.ShouldBindJSON(&rule); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+			return
+		}
+		if rule.Name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+			return
+		}
+
+		if err := manager.AddRule(rule); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusCreated, rule)
+	}
+}
+
+// listRulesHandler lists the currently configured alert rules.
+// @Summary List alert rules
+// @Tags alerts
+// @Produce json
+// @Success 200 {object} map[string]interface{} "data: List of alert rules"
+// @Router /alerts/rules [get]
+func listRulesHandler(manager *Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": manager.Rules()})
+	}
+}
+
+// deleteRuleHandler removes an alert rule by name.
+// @Summary Delete an alert rule
+// @Tags alerts
+// @Produce json
+// @Param name path string true "Rule name"
+// @Success 200 {object} map[string]string "message: Rule deleted"
+// @Failure 404 {object} map[string]string "error: Rule not found"
+// @Router /alerts/rules/{name} [delete]
+func deleteRuleHandler(manager *Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !manager.RemoveRule(c.Param("name")) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Rule not found"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "Rule deleted"})
+	}
+}
+
+// historyHandler returns recorded alert state transitions.
+// @Summary Get alert state history
+// @Tags alerts
+// @Produce json
+// @Param start query string false "Start time for the query (e.g., '-7d')" default(-7d)
+// @Success 200 {object} map[string]interface{} "data: List of alert state transitions"
+// @Failure 500 {object} map[string]string "error: Failed to retrieve history"
+// @Router /alerts/history [get]
+func historyHandler(manager *Manager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := c.DefaultQuery("start", "-7d")
+
+		records, err := manager.History(c.Request.Context(), start)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve history"})
+			return
+		}
+
+		c.JSON(http.StatusOK, gin.H{"data": records})
+	}
+}
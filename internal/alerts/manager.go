@@ -0,0 +1,218 @@
+package alerts
+
+import (
+	"brewing-temperature-monitor-app/internal/handlers"
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+)
+
+// ruleRuntime tracks one rule's hysteresis state alongside its evaluation
+// goroutine's cancel func.
+type ruleRuntime struct {
+	rule   Rule
+	state  State
+	since  time.Time
+	cancel context.CancelFunc
+}
+
+// Manager owns the configured Rules, runs one polling goroutine per rule on
+// its own interval, and transitions each rule through OK -> Pending ->
+// Firing -> OK, dispatching to Notifiers on firing/resolving.
+type Manager struct {
+	handler   *handlers.RecordHandler
+	notifiers []Notifier
+
+	mu    sync.RWMutex
+	rules map[string]*ruleRuntime
+}
+
+// NewManager builds a Manager that evaluates rules against handler and
+// dispatches firing/resolved transitions to notifiers.
+func NewManager(handler *handlers.RecordHandler, notifiers ...Notifier) *Manager {
+	return &Manager{
+		handler:   handler,
+		notifiers: notifiers,
+		rules:     make(map[string]*ruleRuntime),
+	}
+}
+
+// AddRule registers rule, replacing (and stopping) any existing rule with
+// the same name, and starts its evaluation goroutine. It returns an error
+// without registering anything if rule.Every or rule.Window is not a
+// positive duration, since time.NewTicker panics on a non-positive interval.
+func (m *Manager) AddRule(rule Rule) error {
+	if rule.Every.Duration() <= 0 {
+		return fmt.Errorf("rule %q: every must be a positive duration", rule.Name)
+	}
+	if rule.Window.Duration() <= 0 {
+		return fmt.Errorf("rule %q: window must be a positive duration", rule.Name)
+	}
+
+	m.mu.Lock()
+	if existing, ok := m.rules[rule.Name]; ok {
+		existing.cancel()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runtime := &ruleRuntime{rule: rule, state: StateOK, since: time.Now(), cancel: cancel}
+	m.rules[rule.Name] = runtime
+	m.mu.Unlock()
+
+	go m.run(ctx, runtime)
+	return nil
+}
+
+// RemoveRule stops and removes the named rule. ok is false if no such rule
+// was registered.
+func (m *Manager) RemoveRule(name string) (ok bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	runtime, ok := m.rules[name]
+	if !ok {
+		return false
+	}
+
+	runtime.cancel()
+	delete(m.rules, name)
+	return true
+}
+
+// Rules returns the currently configured rules.
+func (m *Manager) Rules() []Rule {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	rules := make([]Rule, 0, len(m.rules))
+	for _, runtime := range m.rules {
+		rules = append(rules, runtime.rule)
+	}
+	return rules
+}
+
+func (m *Manager) run(ctx context.Context, runtime *ruleRuntime) {
+	evaluator := &fluxEvaluator{handler: m.handler, rule: runtime.rule}
+
+	ticker := time.NewTicker(runtime.rule.Every.Duration())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.tick(ctx, runtime, evaluator)
+		}
+	}
+}
+
+func (m *Manager) tick(ctx context.Context, runtime *ruleRuntime, evaluator RuleEvaluator) {
+	observed, value, err := evaluator.Evaluate(ctx)
+	if err != nil {
+		log.Printf("alert rule %q evaluation failed: %v", runtime.rule.Name, err)
+		return
+	}
+
+	m.mu.Lock()
+	prev := runtime.state
+	next := transition(runtime, observed)
+	m.mu.Unlock()
+
+	if next == prev {
+		return
+	}
+
+	m.persist(ctx, runtime.rule, next, value)
+
+	if next == StateFiring || (prev == StateFiring && next == StateOK) {
+		m.dispatch(ctx, runtime.rule, next, value)
+	}
+}
+
+// transition advances runtime's hysteresis state machine given the latest
+// raw observation, holding in Pending for rule.For before actually Firing so
+// a single noisy sample doesn't flap the alert.
+func transition(runtime *ruleRuntime, observed State) State {
+	now := time.Now()
+
+	switch runtime.state {
+	case StateOK:
+		if observed == StateFiring {
+			runtime.state = StatePending
+			runtime.since = now
+		}
+	case StatePending:
+		switch {
+		case observed == StateOK:
+			runtime.state = StateOK
+			runtime.since = now
+		case now.Sub(runtime.since) >= runtime.rule.For.Duration():
+			runtime.state = StateFiring
+			runtime.since = now
+		}
+	case StateFiring:
+		if observed == StateOK {
+			runtime.state = StateOK
+			runtime.since = now
+		}
+	}
+
+	return runtime.state
+}
+
+func (m *Manager) dispatch(ctx context.Context, rule Rule, state State, value float64) {
+	for _, notifier := range m.notifiers {
+		if err := notifier.Notify(ctx, rule, state, value); err != nil {
+			log.Printf("alert rule %q: notifier failed: %v", rule.Name, err)
+		}
+	}
+}
+
+// persist writes the rule's state transition to the "alerts" measurement so
+// it can be queried back via History.
+func (m *Manager) persist(ctx context.Context, rule Rule, state State, value float64) {
+	point := influxdb2.NewPointWithMeasurement("alerts").
+		AddTag("rule", rule.Name).
+		AddField("state", string(state)).
+		AddField("value", value).
+		SetTime(time.Now())
+
+	m.handler.WriteAPI.WritePoint(point)
+}
+
+// History returns the alert state transitions recorded since start (a Flux
+// relative duration such as "-7d").
+func (m *Manager) History(ctx context.Context, start string) ([]map[string]interface{}, error) {
+	query := fmt.Sprintf(`
+		from(bucket: "%s")
+			|> range(start: %s)
+			|> filter(fn: (r) => r._measurement == "alerts")
+			|> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
+	`, m.handler.Bucket, start)
+
+	result, err := handlers.ExecuteQuery(m.handler, query, ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []map[string]interface{}
+	for result.Next() {
+		values := result.Record().Values()
+		records = append(records, map[string]interface{}{
+			"time":  values["_time"],
+			"rule":  values["rule"],
+			"state": values["state"],
+			"value": values["value"],
+		})
+	}
+	if result.Err() != nil {
+		return nil, result.Err()
+	}
+
+	return records, nil
+}
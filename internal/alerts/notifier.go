@@ -0,0 +1,141 @@
+package alerts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/smtp"
+	"os"
+	"strings"
+	"time"
+)
+
+// Notifier dispatches a rule's state transition to an external system.
+type Notifier interface {
+	Notify(ctx context.Context, rule Rule, state State, value float64) error
+}
+
+// Publisher publishes a payload to an MQTT topic. *mqtt.Ingestor satisfies
+// this so alerts can publish back through the same broker connection used
+// for ingestion, without alerts depending on the mqtt package directly.
+type Publisher interface {
+	Publish(topic string, qos byte, payload []byte) error
+}
+
+// WebhookNotifier POSTs a JSON payload describing the alert to URL.
+type WebhookNotifier struct {
+	URL string
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, rule Rule, state State, value float64) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"rule":  rule.Name,
+		"state": state,
+		"value": value,
+		"time":  time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned status %d", n.URL, resp.StatusCode)
+	}
+	return nil
+}
+
+// SMTPNotifier emails the alert via net/smtp.
+type SMTPNotifier struct {
+	Addr string
+	From string
+	To   []string
+}
+
+func (n *SMTPNotifier) Notify(ctx context.Context, rule Rule, state State, value float64) error {
+	subject := fmt.Sprintf("[%s] alert rule %s", strings.ToUpper(string(state)), rule.Name)
+	body := fmt.Sprintf("Rule %q is %s: observed %.2f against threshold %.2f", rule.Name, state, value, rule.Threshold)
+	msg := []byte(fmt.Sprintf("Subject: %s\r\n\r\n%s\r\n", subject, body))
+
+	return smtp.SendMail(n.Addr, smtpAuthFromEnv(n.Addr), n.From, n.To, msg)
+}
+
+func smtpAuthFromEnv(addr string) smtp.Auth {
+	username := os.Getenv("ALERT_SMTP_USERNAME")
+	password := os.Getenv("ALERT_SMTP_PASSWORD")
+	if username == "" || password == "" {
+		return nil
+	}
+
+	host := addr
+	if idx := strings.Index(addr, ":"); idx != -1 {
+		host = addr[:idx]
+	}
+
+	return smtp.PlainAuth("", username, password, host)
+}
+
+// MQTTNotifier publishes the alert as JSON to Topic+rule.Name.
+type MQTTNotifier struct {
+	Publisher Publisher
+	Topic     string
+}
+
+func (n *MQTTNotifier) Notify(ctx context.Context, rule Rule, state State, value float64) error {
+	payload, err := json.Marshal(map[string]interface{}{
+		"rule":  rule.Name,
+		"state": state,
+		"value": value,
+	})
+	if err != nil {
+		return err
+	}
+
+	return n.Publisher.Publish(n.Topic+rule.Name, 0, payload)
+}
+
+// NotifiersFromEnv builds the configured notifiers from ALERT_WEBHOOK_URL,
+// ALERT_SMTP_ADDR/FROM/TO, and mqttPublisher (nil if the MQTT bridge isn't
+// running).
+func NotifiersFromEnv(mqttPublisher Publisher) []Notifier {
+	var notifiers []Notifier
+
+	if url := os.Getenv("ALERT_WEBHOOK_URL"); url != "" {
+		notifiers = append(notifiers, &WebhookNotifier{URL: url})
+	}
+
+	if addr := os.Getenv("ALERT_SMTP_ADDR"); addr != "" {
+		var to []string
+		if raw := os.Getenv("ALERT_SMTP_TO"); raw != "" {
+			to = strings.Split(raw, ",")
+		}
+		notifiers = append(notifiers, &SMTPNotifier{
+			Addr: addr,
+			From: os.Getenv("ALERT_SMTP_FROM"),
+			To:   to,
+		})
+	}
+
+	if mqttPublisher != nil {
+		topic := os.Getenv("ALERT_MQTT_TOPIC")
+		if topic == "" {
+			topic = "alerts/"
+		}
+		notifiers = append(notifiers, &MQTTNotifier{Publisher: mqttPublisher, Topic: topic})
+	}
+
+	return notifiers
+}
@@ -0,0 +1,120 @@
+// Package alerts evaluates threshold rules against the live sensor stream
+// and dispatches notifications when they fire, with hysteresis so a single
+// noisy sample doesn't flap the alert state.
+package alerts
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+)
+
+// Op is the comparison operator a Rule's threshold is checked with.
+type Op string
+
+const (
+	OpGreaterThan    Op = ">"
+	OpGreaterOrEqual Op = ">="
+	OpLessThan       Op = "<"
+	OpLessOrEqual    Op = "<="
+)
+
+// Reducer names the aggregation applied to the window of samples a Rule
+// evaluates, mirroring Grafana's condition reducers.
+type Reducer string
+
+const (
+	ReducerMean         Reducer = "mean"
+	ReducerMax          Reducer = "max"
+	ReducerMin          Reducer = "min"
+	ReducerLast         Reducer = "last"
+	ReducerCountNonNull Reducer = "count_non_null"
+)
+
+// State is a rule's position in the OK -> Pending -> Firing -> OK lifecycle.
+type State string
+
+const (
+	StateOK      State = "ok"
+	StatePending State = "pending"
+	StateFiring  State = "firing"
+)
+
+// Duration wraps time.Duration so Rule files can express windows as
+// human-readable strings ("5m") in both JSON and YAML.
+type Duration time.Duration
+
+// Duration returns the underlying time.Duration.
+func (d Duration) Duration() time.Duration { return time.Duration(d) }
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(raw)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
+
+func (d *Duration) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var raw string
+	if err := unmarshal(&raw); err != nil {
+		return err
+	}
+
+	parsed, err := time.ParseDuration(raw)
+	if err != nil {
+		return err
+	}
+
+	*d = Duration(parsed)
+	return nil
+}
+
+// Rule defines a threshold condition evaluated on a schedule against an
+// aggregated query, e.g. "fire if mean(temperature, 5m) for device_id=X
+// exceeds 22 for 3 consecutive windows".
+type Rule struct {
+	Name      string   `json:"name" yaml:"name"`
+	DeviceID  string   `json:"deviceId,omitempty" yaml:"deviceId,omitempty"`
+	Location  string   `json:"location,omitempty" yaml:"location,omitempty"`
+	Field     string   `json:"field" yaml:"field"`
+	Reducer   Reducer  `json:"reducer" yaml:"reducer"`
+	Op        Op       `json:"op" yaml:"op"`
+	Threshold float64  `json:"threshold" yaml:"threshold"`
+	Window    Duration `json:"window" yaml:"window"` // lookback the reducer is applied over, e.g. "5m"
+	For       Duration `json:"for" yaml:"for"`       // time the condition must hold before Firing
+	Every     Duration `json:"every" yaml:"every"`   // how often the rule is evaluated
+}
+
+// RuleEvaluator evaluates a Rule's condition against its current data,
+// returning the raw (non-hysteresis) state and the observed value.
+type RuleEvaluator interface {
+	Evaluate(ctx context.Context) (State, float64, error)
+}
+
+// breaches reports whether value satisfies op against threshold.
+func breaches(op Op, value, threshold float64) bool {
+	switch op {
+	case OpGreaterThan:
+		return value > threshold
+	case OpGreaterOrEqual:
+		return value >= threshold
+	case OpLessThan:
+		return value < threshold
+	case OpLessOrEqual:
+		return value <= threshold
+	default:
+		return false
+	}
+}
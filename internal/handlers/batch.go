@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"bufio"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+
+	"brewing-temperature-monitor-app/internal/models"
+
+	"github.com/gin-gonic/gin"
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+	"github.com/influxdata/influxdb-client-go/v2/api/write"
+)
+
+const lineProtocolContentType = "application/vnd.influx.line-protocol"
+
+// WriteOptionsFromEnv builds the InfluxDB client write options from
+// WRITE_BATCH_SIZE, WRITE_FLUSH_INTERVAL_MS and WRITE_MAX_RETRIES, falling
+// back to the client library defaults when a variable is unset or invalid.
+func WriteOptionsFromEnv() *influxdb2.Options {
+	options := influxdb2.DefaultOptions()
+
+	if batchSize, ok := envUint("WRITE_BATCH_SIZE"); ok {
+		options.SetBatchSize(batchSize)
+	}
+	if flushIntervalMs, ok := envUint("WRITE_FLUSH_INTERVAL_MS"); ok {
+		options.SetFlushInterval(flushIntervalMs)
+	}
+	if maxRetries, ok := envUint("WRITE_MAX_RETRIES"); ok {
+		options.SetMaxRetries(maxRetries)
+	}
+
+	return options
+}
+
+func envUint(name string) (uint, bool) {
+	value := os.Getenv(name)
+	if value == "" {
+		return 0, false
+	}
+
+	parsed, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		log.Printf("Invalid value for %s=%q, using default: %v", name, value, err)
+		return 0, false
+	}
+
+	return uint(parsed), true
+}
+
+// logWriteErrors drains the async WriteAPI's error channel so write failures
+// (e.g. a malformed point in a batch) surface in the logs instead of being
+// silently dropped.
+func logWriteErrors(writeAPI api.WriteAPI) {
+	go func() {
+		for err := range writeAPI.Errors() {
+			log.Printf("Error writing batched point(s): %v", err)
+		}
+	}()
+}
+
+// sensorDataPoint builds the same sensor_data point PostData writes, so
+// single and batched ingestion stay in lockstep.
+func sensorDataPoint(data models.SensorData) *write.Point {
+	return influxdb2.NewPointWithMeasurement("sensor_data").
+		AddTag("device_id", data.DeviceID).
+		AddField("temperature", data.Temperature).
+		AddField("humidity", data.Humidity).
+		AddField("location", data.Location).
+		SetTime(data.TimestampSampled)
+}
+
+// PostBatchData ingests many sensor records in a single request through the
+// async batch writer, either as a JSON array of models.SensorData or as
+// newline-delimited Influx line protocol.
+// @Summary Submit a batch of sensor data
+// @Description Submit many sensor records at once via the async batch writer
+// @Tags records
+// @Accept json
+// @Produce json
+// @Param data body []models.SensorData true "Sensor data records to submit"
+// @Success 202 {object} map[string]interface{} "message: Batch accepted, count: number of records enqueued"
+// @Failure 400 {object} map[string]string "error: Invalid input or humidity out of range"
+// @Router /records/batch [post]
+func (h *RecordHandler) PostBatchData(c *gin.Context) {
+	if c.ContentType() == lineProtocolContentType {
+		h.postBatchLineProtocol(c)
+		return
+	}
+
+	var batch []models.SensorData
+	if err := c.ShouldBindJSON(&batch); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input"})
+		return
+	}
+
+	for _, data := range batch {
+		if data.Humidity < 0 || data.Humidity > 100.0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Humidity Must be in range [0.0, 100.00]"})
+			return
+		}
+	}
+
+	for _, data := range batch {
+		h.WriteAPI.WritePoint(sensorDataPoint(data))
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Batch accepted", "count": len(batch)})
+}
+
+// postBatchLineProtocol enqueues one point per non-empty line of Influx line
+// protocol from the request body.
+func (h *RecordHandler) postBatchLineProtocol(c *gin.Context) {
+	scanner := bufio.NewScanner(c.Request.Body)
+
+	count := 0
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+
+		h.WriteAPI.WriteRecord(line)
+		count++
+	}
+
+	if err := scanner.Err(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Failed to read line protocol body: %v", err)})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Batch accepted", "count": count})
+}
@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/influxdata/influxdb-client-go/v2/domain"
+)
+
+// retentionPolicyEnabled controls whether EnsureBuckets/RegisterDownsampleTasks
+// actually provision retention rules and downsample tasks. Tests (and anyone
+// backfilling historical timestamps) can set RETENTION_POLICY_ENABLED=false so
+// old points don't get expired out from under them.
+func retentionPolicyEnabled() bool {
+	return os.Getenv("RETENTION_POLICY_ENABLED") != "false"
+}
+
+// weeklyBucket, monthlyBucket and foreverBucket name the downsample tiers
+// derived from the raw bucket configured on the handler.
+func (h *RecordHandler) weeklyBucket() string  { return h.Bucket + "_weekly" }
+func (h *RecordHandler) monthlyBucket() string { return h.Bucket + "_monthly" }
+func (h *RecordHandler) foreverBucket() string { return h.Bucket + "_forever" }
+
+// bucketRetention pairs a bucket name with how long it should keep data.
+// EverySeconds of 0 means "keep forever".
+type bucketRetention struct {
+	name         string
+	everySeconds int64
+}
+
+func (h *RecordHandler) tieredBuckets() []bucketRetention {
+	return []bucketRetention{
+		{h.Bucket, 15 * 24 * 60 * 60},               // raw samples, ~15 days
+		{h.weeklyBucket(), 9 * 7 * 24 * 60 * 60},    // hourly means, ~9 weeks
+		{h.monthlyBucket(), 25 * 30 * 24 * 60 * 60}, // daily means, ~25 months
+		{h.foreverBucket(), 0},                      // weekly means, kept forever
+	}
+}
+
+// EnsureBuckets makes sure the raw bucket plus the weekly/monthly/forever
+// downsample tiers exist with the expected retention, creating whichever are
+// missing. It is a no-op when RETENTION_POLICY_ENABLED=false.
+func (h *RecordHandler) EnsureBuckets(ctx context.Context) error {
+	if !retentionPolicyEnabled() {
+		log.Printf("retention policy disabled, skipping bucket provisioning")
+		return nil
+	}
+
+	org, err := h.InfluxClient.OrganizationsAPI().FindOrganizationByName(ctx, h.Org)
+	if err != nil {
+		return fmt.Errorf("failed to resolve org %q: %w", h.Org, err)
+	}
+
+	bucketsAPI := h.InfluxClient.BucketsAPI()
+	for _, tier := range h.tieredBuckets() {
+		existing, err := bucketsAPI.FindBucketByName(ctx, tier.name)
+		if err == nil && existing != nil {
+			continue
+		}
+
+		rule := domain.RetentionRule{EverySeconds: tier.everySeconds}
+		if _, err := bucketsAPI.CreateBucketWithName(ctx, org, tier.name, rule); err != nil {
+			return fmt.Errorf("failed to create bucket %q: %w", tier.name, err)
+		}
+		log.Printf("provisioned bucket %q (retention: %ds)", tier.name, tier.everySeconds)
+	}
+
+	return nil
+}
+
+// RegisterDownsampleTasks creates the InfluxDB Tasks that roll raw points up
+// through the weekly -> monthly -> forever tiers, grouped by device_id so
+// existing Flux filters over that tag keep working. (location is written as
+// a field, not a tag, so it can't be a group key.) Task names aren't unique
+// in InfluxDB, so it first lists existing tasks and skips creating ones that
+// are already registered, making it safe to call on every startup. It is a
+// no-op when RETENTION_POLICY_ENABLED=false.
+func (h *RecordHandler) RegisterDownsampleTasks(ctx context.Context) error {
+	if !retentionPolicyEnabled() {
+		return nil
+	}
+
+	tasksAPI := h.InfluxClient.TasksAPI()
+	org, err := h.InfluxClient.OrganizationsAPI().FindOrganizationByName(ctx, h.Org)
+	if err != nil {
+		return fmt.Errorf("failed to resolve org %q: %w", h.Org, err)
+	}
+
+	existingTasks, err := tasksAPI.FindTasks(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to list existing tasks: %w", err)
+	}
+	existingTaskNames := make(map[string]bool, len(existingTasks))
+	for _, task := range existingTasks {
+		existingTaskNames[task.Name] = true
+	}
+
+	downsamples := []struct {
+		name      string
+		every     string
+		srcBucket string
+		dstBucket string
+		window    string
+	}{
+		{"downsample_raw_to_weekly", "1h", h.Bucket, h.weeklyBucket(), "1h"},
+		{"downsample_weekly_to_monthly", "1d", h.weeklyBucket(), h.monthlyBucket(), "1d"},
+		{"downsample_monthly_to_forever", "7d", h.monthlyBucket(), h.foreverBucket(), "7d"},
+	}
+
+	for _, ds := range downsamples {
+		if existingTaskNames[ds.name] {
+			continue
+		}
+
+		flux := fmt.Sprintf(`
+			from(bucket: "%s")
+				|> range(start: -%s)
+				|> filter(fn: (r) => r._measurement == "sensor_data")
+				|> filter(fn: (r) => r._field == "temperature" or r._field == "humidity")
+				|> aggregateWindow(every: %s, fn: mean, createEmpty: false)
+				|> set(key: "_aggregate", value: "mean")
+				|> group(columns: ["device_id", "_aggregate"])
+				|> to(bucket: "%s", org: "%s")
+		`, ds.srcBucket, ds.every, ds.window, ds.dstBucket, h.Org)
+
+		if _, err := tasksAPI.CreateTaskWithEvery(ctx, ds.name, flux, ds.every, *org.Id); err != nil {
+			return fmt.Errorf("failed to register task %q: %w", ds.name, err)
+		}
+		log.Printf("registered downsample task %q (%s -> %s every %s)", ds.name, ds.srcBucket, ds.dstBucket, ds.every)
+	}
+
+	return nil
+}
+
+// relativeDurationPattern matches Flux-style relative start times such as
+// "-30d", "-1y" or "-6h".
+var relativeDurationPattern = regexp.MustCompile(`^-(\d+)(mo|[smhdwy])$`)
+
+// parseRelativeDuration converts a Flux relative duration literal into a Go
+// time.Duration. ok is false when value isn't a relative duration (e.g. an
+// absolute RFC3339 timestamp), in which case the caller should fall back to
+// the raw bucket.
+func parseRelativeDuration(value string) (age time.Duration, ok bool) {
+	matches := relativeDurationPattern.FindStringSubmatch(value)
+	if matches == nil {
+		return 0, false
+	}
+
+	amount, err := strconv.Atoi(matches[1])
+	if err != nil {
+		return 0, false
+	}
+
+	var unit time.Duration
+	switch matches[2] {
+	case "s":
+		unit = time.Second
+	case "m":
+		unit = time.Minute
+	case "h":
+		unit = time.Hour
+	case "d":
+		unit = 24 * time.Hour
+	case "w":
+		unit = 7 * 24 * time.Hour
+	case "mo":
+		unit = 30 * 24 * time.Hour
+	case "y":
+		unit = 365 * 24 * time.Hour
+	default:
+		return 0, false
+	}
+
+	return time.Duration(amount) * unit, true
+}
+
+// resolveBucket picks the source bucket tier that can satisfy a query
+// starting at start, so that e.g. a "-1y" query hits the monthly bucket
+// instead of scanning raw points. Unparseable (e.g. absolute) start values
+// fall back to the raw bucket.
+func (h *RecordHandler) resolveBucket(start string) string {
+	age, ok := parseRelativeDuration(start)
+	if !ok {
+		return h.Bucket
+	}
+
+	switch {
+	case age <= 15*24*time.Hour:
+		return h.Bucket
+	case age <= 9*7*24*time.Hour:
+		return h.weeklyBucket()
+	case age <= 25*30*24*time.Hour:
+		return h.monthlyBucket()
+	default:
+		return h.foreverBucket()
+	}
+}
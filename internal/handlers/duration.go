@@ -0,0 +1,98 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// allowedAggFreqs whitelists the Flux duration literals callers may pass as
+// aggFreq, so user input never ends up concatenated into a query as
+// arbitrary Flux.
+var allowedAggFreqs = []string{"1m", "5m", "15m", "1h", "1d", "7d"}
+
+// durationPreset resolves a durationKey into the start/stop range, source
+// bucket and default aggregation frequency that should satisfy it.
+type durationPreset struct {
+	start          string
+	defaultAggFreq string
+	bucket         func(h *RecordHandler) string
+}
+
+var durationPresets = map[string]durationPreset{
+	"hour":    {start: "-1h", defaultAggFreq: "1m", bucket: func(h *RecordHandler) string { return h.Bucket }},
+	"day":     {start: "-1d", defaultAggFreq: "5m", bucket: func(h *RecordHandler) string { return h.Bucket }},
+	"week":    {start: "-7d", defaultAggFreq: "1h", bucket: func(h *RecordHandler) string { return h.weeklyBucket() }},
+	"month":   {start: "-30d", defaultAggFreq: "1d", bucket: func(h *RecordHandler) string { return h.monthlyBucket() }},
+	"year":    {start: "-1y", defaultAggFreq: "7d", bucket: func(h *RecordHandler) string { return h.monthlyBucket() }},
+	"forever": {start: "1970-01-01T00:00:00Z", defaultAggFreq: "7d", bucket: func(h *RecordHandler) string { return h.foreverBucket() }},
+}
+
+// resolveDuration maps a durationKey (hour/day/week/month/year/forever) to
+// its (start, stop, bucket, defaultAggFreq). ok is false for an unknown key.
+func (h *RecordHandler) resolveDuration(key string) (start, stop, bucket, defaultAggFreq string, ok bool) {
+	preset, ok := durationPresets[key]
+	if !ok {
+		return "", "", "", "", false
+	}
+
+	return preset.start, time.Now().UTC().Format("2006-01-02T15:04:05Z"), preset.bucket(h), preset.defaultAggFreq, true
+}
+
+// defaultStart is the query window used when the caller gives no start,
+// stop or duration at all. It's kept within the raw bucket's retention
+// (see tieredBuckets) so the most common, param-less call reads back the
+// data a client just POSTed instead of silently falling through to an
+// empty downsample tier.
+const defaultStart = "-15d"
+
+// applyDuration fills in params.Start/Stop (and Bucket/AggFreq defaults) from
+// params.Duration when the caller hasn't supplied explicit start/stop. If
+// both are given, the explicit start/stop win and a warning is logged. It
+// returns the bucket the query should read from.
+func (h *RecordHandler) applyDuration(params *QueryParams) (bucket string, err error) {
+	explicitRange := params.Start != "" || params.Stop != ""
+
+	if explicitRange {
+		if params.Duration != "" {
+			log.Printf("both duration=%q and explicit start/stop provided; using explicit start/stop", params.Duration)
+		}
+		if params.Start == "" {
+			params.Start = defaultStart
+		}
+		if params.Stop == "" {
+			params.Stop = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+		}
+		return h.resolveBucket(params.Start), nil
+	}
+
+	if params.Duration == "" {
+		params.Start = defaultStart
+		params.Stop = time.Now().UTC().Format("2006-01-02T15:04:05Z")
+		return h.resolveBucket(params.Start), nil
+	}
+
+	start, stop, durationBucket, defaultAggFreq, ok := h.resolveDuration(params.Duration)
+	if !ok {
+		return "", fmt.Errorf("unsupported duration %q", params.Duration)
+	}
+
+	params.Start = start
+	params.Stop = stop
+	if params.AggFreq == "" {
+		params.AggFreq = defaultAggFreq
+	}
+
+	return durationBucket, nil
+}
+
+// validAggFreq reports whether freq is one of the whitelisted Flux duration
+// literals, preventing arbitrary Flux from being injected via aggFreq.
+func validAggFreq(freq string) bool {
+	for _, allowed := range allowedAggFreqs {
+		if freq == allowed {
+			return true
+		}
+	}
+	return false
+}
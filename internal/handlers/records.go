@@ -8,7 +8,6 @@ import (
 	"log"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
@@ -24,10 +23,11 @@ var aggregationsMapper = map[string]string{
 }
 
 type QueryParams struct {
-	Start   string `form:"start" example:"-30d"`                // Start time for the query (e.g., "-30d")
-	Stop    string `form:"stop" example:"2023-10-01T00:00:00Z"` // Stop time for the query (e.g., "2023-10-01T00:00:00Z")
-	Aggr    string `form:"aggr" example:"mean"`                 // Aggregation function (e.g., "mean"), or mean,sum,max
-	AggFreq string `form:"aggFreq" example:"1d"`                // Aggregation frequency (e.g., "1d")
+	Start    string `form:"start" example:"-30d"`                // Start time for the query (e.g., "-30d")
+	Stop     string `form:"stop" example:"2023-10-01T00:00:00Z"` // Stop time for the query (e.g., "2023-10-01T00:00:00Z")
+	Duration string `form:"duration" example:"week"`             // Duration key (hour/day/week/month/year/forever), ignored if start/stop are set
+	Aggr     string `form:"aggr" example:"mean"`                 // Aggregation function (e.g., "mean"), or mean,sum,max
+	AggFreq  string `form:"aggFreq" example:"1d"`                // Aggregation frequency (e.g., "1d")
 }
 
 // RecordHandler holds the InfluxDB client
@@ -35,10 +35,13 @@ type RecordHandler struct {
 	InfluxClient influxdb2.Client
 	Org          string
 	Bucket       string
+	WriteAPI     api.WriteAPI
 }
 
-// execureQuery is a helper method that executes queries
-func executeQuery(h *RecordHandler, query string, ctx context.Context) (*api.QueryTableResult, error) {
+// ExecuteQuery is a helper that executes a Flux query against h's org, for
+// other subsystems (e.g. internal/alerts) that need to run ad-hoc queries
+// through the same InfluxDB client.
+func ExecuteQuery(h *RecordHandler, query string, ctx context.Context) (*api.QueryTableResult, error) {
 	queryAPI := h.InfluxClient.QueryAPI(h.Org)
 
 	fmt.Printf("QUERY: %v\n", query)
@@ -48,10 +51,14 @@ func executeQuery(h *RecordHandler, query string, ctx context.Context) (*api.Que
 }
 
 func NewRecordHandler(client influxdb2.Client, org, bucket string) *RecordHandler {
+	writeAPI := client.WriteAPI(org, bucket)
+	logWriteErrors(writeAPI)
+
 	return &RecordHandler{
 		InfluxClient: client,
 		Org:          org,
 		Bucket:       bucket,
+		WriteAPI:     writeAPI,
 	}
 }
 
@@ -62,9 +69,8 @@ func NewRecordHandler(client influxdb2.Client, org, bucket string) *RecordHandle
 // @Accept json
 // @Produce json
 // @Param data body models.SensorData true "Sensor data to submit"
-// @Success 200 {object} map[string]interface{} "message: Data stored successfully, data: submitted data"
+// @Success 202 {object} map[string]interface{} "message: Data accepted for storage, data: submitted data"
 // @Failure 400 {object} map[string]string "error: Invalid input or humidity out of range"
-// @Failure 500 {object} map[string]string "error: Failed to store data"
 // @Router /records [post]
 func (h *RecordHandler) PostData(c *gin.Context) {
 	var data models.SensorData
@@ -80,21 +86,9 @@ func (h *RecordHandler) PostData(c *gin.Context) {
 
 	}
 
-	point := influxdb2.NewPointWithMeasurement("sensor_data").
-		AddTag("device_id", data.DeviceID).
-		AddField("temperature", data.Temperature).
-		AddField("humidity", data.Humidity).
-		AddField("location", data.Location).
-		SetTime(data.TimestampSampled)
-
-	writeAPI := h.InfluxClient.WriteAPIBlocking(h.Org, h.Bucket)
-	if err := writeAPI.WritePoint(context.Background(), point); err != nil {
-		log.Printf("Error writing to database: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to store data"})
-		return
-	}
+	h.WriteAPI.WritePoint(sensorDataPoint(data))
 
-	c.JSON(http.StatusOK, gin.H{"message": "Data stored successfully", "data": data})
+	c.JSON(http.StatusAccepted, gin.H{"message": "Data accepted for storage", "data": data})
 }
 
 // GetAllData fetches all records from the database
@@ -102,30 +96,34 @@ func (h *RecordHandler) PostData(c *gin.Context) {
 // @Description Retrieve all sensor data within a specified time range
 // @Tags records
 // @Produce json
-// @Param start query string false "Start time for the query (e.g., '-30d')" default(-30d)
+// @Param start query string false "Start time for the query (e.g., '-15d')" default(-15d)
 // @Param stop query string false "Stop time for the query (e.g., '2023-10-01T00:00:00Z'), if left empty gets current datetime" default()
+// @Param duration query string false "Duration key (hour/day/week/month/year/forever), ignored if start/stop are set"
 // @Success 200 {object} map[string]interface{} "data: List of sensor records"
 // @Failure 400 {object} map[string]string "error: Invalid query parameters"
 // @Failure 500 {object} map[string]string "error: Failed to retrieve data"
 // @Router /records [get]
 func (h *RecordHandler) GetAllData(c *gin.Context) {
 
-	params := QueryParams{
-		Start: "-30d",
-		Stop:  time.Now().UTC().Format("2006-01-02T15:04:05Z"),
-	}
+	var params QueryParams
 
 	if err := c.ShouldBindQuery(&params); err != nil {
 		c.JSON(400, gin.H{"error": "Invalid query parameters"})
 		return
 	}
 
+	bucket, err := h.applyDuration(&params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	query := fmt.Sprintf(`
-	from(bucket: "`+h.Bucket+`")
+	from(bucket: "%s")
 	|> range(start: %s, stop: %s)
-	|> filter(fn: (r) => r._measurement == "sensor_data") 
+	|> filter(fn: (r) => r._measurement == "sensor_data")
 	  |> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
-`, params.Start, params.Stop)
+`, bucket, params.Start, params.Stop)
 
 	fmt.Printf("QUERY: %v\n", query)
 
@@ -167,10 +165,11 @@ func (h *RecordHandler) GetAllData(c *gin.Context) {
 // @Tags records
 // @Produce json
 // @Param deviceId path string true "Device ID"
-// @Param start query string false "Start time for the query (e.g., '-30d')" default(-30d)
+// @Param start query string false "Start time for the query (e.g., '-15d')" default(-15d)
 // @Param stop query string false "Stop time for the query (e.g., '2023-10-01T00:00:00Z'), if left empty gets current datetime" default()
 // @Param aggr query string false "Aggregation function (e.g., 'mean', or mean,max,min for multiple aggregations)"
 // @Param aggFreq query string false "Aggregation frequency (e.g., '1d')" default(1d)
+// @Param duration query string false "Duration key (hour/day/week/month/year/forever), ignored if start/stop are set"
 // @Success 200 {object} map[string]interface{} "data: List of sensor records"
 // @Failure 400 {object} map[string]string "error: Invalid query parameters or missing deviceId"
 // @Failure 500 {object} map[string]string "error: Failed to retrieve data"
@@ -178,18 +177,21 @@ func (h *RecordHandler) GetAllData(c *gin.Context) {
 func (h *RecordHandler) GetDataFromDeviceByID(c *gin.Context) {
 	deviceId := c.Param("deviceId") // Extract deviceId from URL path
 
-	params := QueryParams{
-		Start:   "-30d",
-		Stop:    time.Now().UTC().Format("2006-01-02T15:04:05Z"),
-		Aggr:    "",
-		AggFreq: "1d",
-	}
-
+	var params QueryParams
 	if err := c.ShouldBindQuery(&params); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid query parameters"})
 		return
 	}
 
+	bucket, err := h.applyDuration(&params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if params.AggFreq == "" {
+		params.AggFreq = "1d"
+	}
+
 	var query string
 	if params.Aggr == "" {
 		query = fmt.Sprintf(`
@@ -198,9 +200,13 @@ func (h *RecordHandler) GetDataFromDeviceByID(c *gin.Context) {
 			|> filter(fn: (r) => r._measurement == "sensor_data")
 			|> filter(fn: (r) => r["device_id"] == "%s")
 			|> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
-		`, h.Bucket, params.Start, params.Stop, deviceId)
+		`, bucket, params.Start, params.Stop, deviceId)
 	} else {
-		query = h.buildAggregatedQuery(deviceId, params)
+		query, err = h.buildAggregatedQuery(bucket, deviceId, params)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 	}
 
 	records, err := h.executeAndProcessQuery(c, query)
@@ -218,8 +224,9 @@ func (h *RecordHandler) GetDataFromDeviceByID(c *gin.Context) {
 // @Tags records
 // @Produce json
 // @Param location path string true "Location"
-// @Param start query string false "Start time for the query (e.g., '-30d')" default(-30d)
+// @Param start query string false "Start time for the query (e.g., '-15d')" default(-15d)
 // @Param stop query string false "Stop time for the query (e.g., '2023-10-01T00:00:00Z'), if left empty gets current datetime" default()
+// @Param duration query string false "Duration key (hour/day/week/month/year/forever), ignored if start/stop are set"
 // @Success 200 {object} map[string]interface{} "data: List of sensor records"
 // @Failure 400 {object} map[string]string "error: Invalid query parameters or missing location"
 // @Failure 500 {object} map[string]string "error: Failed to retrieve data"
@@ -228,10 +235,7 @@ func (h *RecordHandler) GetDataFromDeviceByLocation(c *gin.Context) {
 
 	location := c.Param("location") // Extract deviceId from URL path
 
-	params := QueryParams{
-		Start: "-30d",
-		Stop:  time.Now().UTC().Format("2006-01-02T15:04:05Z"),
-	}
+	var params QueryParams
 
 	// Bind query parameters to the struct
 	if err := c.ShouldBindQuery(&params); err != nil {
@@ -245,14 +249,20 @@ func (h *RecordHandler) GetDataFromDeviceByLocation(c *gin.Context) {
 		return
 	}
 
+	bucket, err := h.applyDuration(&params)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	// Construct Flux query to retrieve all data for the given device
 	query := fmt.Sprintf(`
-		from(bucket: "`+h.Bucket+`")
-		|> range(start: %s, stop: %s) 
+		from(bucket: "%s")
+		|> range(start: %s, stop: %s)
 		|> filter(fn: (r) => r._measurement == "sensor_data")
   		|> pivot(rowKey:["_time"], columnKey: ["_field"], valueColumn: "_value")
 		|> filter(fn: (r) => r["location"] == "%s")
-	`, params.Start, params.Stop, location)
+	`, bucket, params.Start, params.Stop, location)
 
 	fmt.Printf("QUERY: %v\n", query)
 
@@ -289,7 +299,18 @@ func (h *RecordHandler) GetDataFromDeviceByLocation(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": records})
 }
 
-func (h *RecordHandler) buildAggregatedQuery(deviceID string, params QueryParams) string {
+func (h *RecordHandler) buildAggregatedQuery(bucket, deviceID string, params QueryParams) (string, error) {
+	aggrFunctions := strings.Split(params.Aggr, ",")
+	for _, aggFunc := range aggrFunctions {
+		if !helpers.IsInArray(aggFunc, supportedAggregations) {
+			return "", fmt.Errorf("unsupported aggregation %q", aggFunc)
+		}
+	}
+
+	if !validAggFreq(params.AggFreq) {
+		return "", fmt.Errorf("unsupported aggFreq %q", params.AggFreq)
+	}
+
 	var queryBuilder strings.Builder
 
 	// Base query
@@ -299,9 +320,7 @@ func (h *RecordHandler) buildAggregatedQuery(deviceID string, params QueryParams
 			|> filter(fn: (r) => r._measurement == "sensor_data")
 			|> filter(fn: (r) => r["device_id"] == "%s")
 			|> filter(fn: (r) => r._field == "temperature" or r._field == "humidity")
-	`, h.Bucket, params.Start, params.Stop, deviceID))
-
-	aggrFunctions := strings.Split(params.Aggr, ",")
+	`, bucket, params.Start, params.Stop, deviceID))
 
 	// Single aggregation case
 	if len(aggrFunctions) == 1 {
@@ -331,7 +350,7 @@ func (h *RecordHandler) buildAggregatedQuery(deviceID string, params QueryParams
 		`, strings.Join(aggrFunctions, "_data,")+"_data"))
 	}
 
-	return queryBuilder.String()
+	return queryBuilder.String(), nil
 }
 
 func (h *RecordHandler) executeAndProcessQuery(c *gin.Context, query string) ([]map[string]interface{}, error) {
@@ -374,6 +393,7 @@ func (h *RecordHandler) executeAndProcessQuery(c *gin.Context, query string) ([]
 
 func RegisterRoutes(router *gin.Engine, recordHandler *RecordHandler) {
 	router.POST("/records", recordHandler.PostData)
+	router.POST("/records/batch", recordHandler.PostBatchData)
 	router.GET("/records", recordHandler.GetAllData)
 	router.GET("/records/devices/:deviceId", recordHandler.GetDataFromDeviceByID)
 	router.GET("/records/locations/:location", recordHandler.GetDataFromDeviceByLocation)
@@ -0,0 +1,183 @@
+// Package mqtt bridges an MQTT broker into the same InfluxDB pipeline as the
+// HTTP intake, for deployments where sensors publish directly to a broker
+// instead of calling the REST API.
+package mqtt
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"brewing-temperature-monitor-app/internal/models"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+)
+
+// Config holds the MQTT broker settings read from the environment.
+type Config struct {
+	Broker   string
+	Username string
+	Password string
+	Topic    string
+	QoS      byte
+}
+
+// ConfigFromEnv builds a Config from MQTT_BROKER, MQTT_USERNAME,
+// MQTT_PASSWORD, MQTT_TOPIC and MQTT_QOS. ok is false when MQTT_BROKER is
+// unset, meaning the MQTT bridge should stay disabled.
+func ConfigFromEnv() (config Config, ok bool) {
+	broker := os.Getenv("MQTT_BROKER")
+	if broker == "" {
+		return Config{}, false
+	}
+
+	topic := os.Getenv("MQTT_TOPIC")
+	if topic == "" {
+		topic = "sensors/+/data"
+	}
+
+	qos := byte(0)
+	if raw := os.Getenv("MQTT_QOS"); raw != "" {
+		if parsed, err := strconv.Atoi(raw); err == nil && parsed >= 0 && parsed <= 2 {
+			qos = byte(parsed)
+		}
+	}
+
+	return Config{
+		Broker:   broker,
+		Username: os.Getenv("MQTT_USERNAME"),
+		Password: os.Getenv("MQTT_PASSWORD"),
+		Topic:    topic,
+		QoS:      qos,
+	}, true
+}
+
+// Ingestor subscribes to an MQTT topic and forwards published sensor
+// readings into InfluxDB through the shared batch writer.
+type Ingestor struct {
+	config    Config
+	writeAPI  api.WriteAPI
+	client    mqtt.Client
+	connected atomic.Bool
+}
+
+// NewIngestor builds an Ingestor that writes through writeAPI, the same
+// async WriteAPI used by the HTTP handlers.
+func NewIngestor(config Config, writeAPI api.WriteAPI) *Ingestor {
+	return &Ingestor{config: config, writeAPI: writeAPI}
+}
+
+// Start connects to the broker and subscribes to the configured topic. The
+// underlying client reconnects with exponential backoff on connection loss.
+func (in *Ingestor) Start() error {
+	opts := mqtt.NewClientOptions().
+		AddBroker(in.config.Broker).
+		SetClientID("brewing-temperature-monitor-app").
+		SetUsername(in.config.Username).
+		SetPassword(in.config.Password).
+		SetAutoReconnect(true).
+		SetConnectRetryInterval(2 * time.Second).
+		SetMaxReconnectInterval(time.Minute).
+		SetOnConnectHandler(in.onConnect).
+		SetConnectionLostHandler(in.onConnectionLost)
+
+	in.client = mqtt.NewClient(opts)
+
+	token := in.client.Connect()
+	if token.Wait() && token.Error() != nil {
+		return fmt.Errorf("failed to connect to MQTT broker %s: %w", in.config.Broker, token.Error())
+	}
+
+	return nil
+}
+
+// Stop disconnects from the broker.
+func (in *Ingestor) Stop() {
+	if in.client != nil {
+		in.client.Disconnect(250)
+	}
+}
+
+// Connected reports whether the MQTT client currently has a live connection,
+// for exposing via /healthz.
+func (in *Ingestor) Connected() bool {
+	return in.connected.Load()
+}
+
+// Publish sends payload to topic over the ingestor's existing connection, so
+// other subsystems (e.g. internal/alerts) can publish back without opening a
+// second MQTT client.
+func (in *Ingestor) Publish(topic string, qos byte, payload []byte) error {
+	if in.client == nil {
+		return fmt.Errorf("mqtt ingestor not started")
+	}
+
+	token := in.client.Publish(topic, qos, false, payload)
+	token.Wait()
+	return token.Error()
+}
+
+func (in *Ingestor) onConnect(c mqtt.Client) {
+	in.connected.Store(true)
+	log.Printf("MQTT connected to %s", in.config.Broker)
+
+	if token := c.Subscribe(in.config.Topic, in.config.QoS, in.handleMessage); token.Wait() && token.Error() != nil {
+		log.Printf("Error subscribing to %s: %v", in.config.Topic, token.Error())
+	}
+}
+
+func (in *Ingestor) onConnectionLost(_ mqtt.Client, err error) {
+	in.connected.Store(false)
+	log.Printf("MQTT connection lost: %v", err)
+}
+
+func (in *Ingestor) handleMessage(_ mqtt.Client, msg mqtt.Message) {
+	var data models.SensorData
+	if err := json.Unmarshal(msg.Payload(), &data); err != nil {
+		log.Printf("Error decoding MQTT payload on %s: %v", msg.Topic(), err)
+		return
+	}
+
+	if data.DeviceID == "" {
+		data.DeviceID = deviceIDFromTopic(in.config.Topic, msg.Topic())
+	}
+	if data.TimestampSampled.IsZero() {
+		data.TimestampSampled = time.Now().UTC()
+	}
+
+	point := influxdb2.NewPointWithMeasurement("sensor_data").
+		AddTag("device_id", data.DeviceID).
+		AddField("temperature", data.Temperature).
+		AddField("humidity", data.Humidity).
+		AddField("location", data.Location).
+		SetTime(data.TimestampSampled)
+
+	in.writeAPI.WritePoint(point)
+}
+
+// deviceIDFromTopic extracts the device id from the single-level wildcard
+// ("+") segment of pattern, e.g. pattern "sensors/+/data" against topic
+// "sensors/sensor_123/data" yields "sensor_123". It returns "" if pattern has
+// no wildcard or the segment counts don't line up.
+func deviceIDFromTopic(pattern, topic string) string {
+	patternParts := strings.Split(pattern, "/")
+	topicParts := strings.Split(topic, "/")
+	if len(patternParts) != len(topicParts) {
+		return ""
+	}
+
+	for i, part := range patternParts {
+		if part == "+" {
+			return topicParts[i]
+		}
+	}
+
+	return ""
+}
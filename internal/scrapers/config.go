@@ -0,0 +1,49 @@
+package scrapers
+
+import (
+	"os"
+	"time"
+)
+
+// ScraperFromEnv builds a single HTTPJSONScraper from SCRAPER_URL plus its
+// field-mapping and interval env vars. ok is false when SCRAPER_URL is unset.
+func ScraperFromEnv() (scraper *HTTPJSONScraper, ok bool) {
+	url := os.Getenv("SCRAPER_URL")
+	if url == "" {
+		return nil, false
+	}
+
+	name := os.Getenv("SCRAPER_NAME")
+	if name == "" {
+		name = "external_feed"
+	}
+
+	interval := 5 * time.Minute
+	if raw := os.Getenv("SCRAPER_INTERVAL"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			interval = parsed
+		}
+	}
+
+	mapping := FieldMapping{
+		Temperature: envDefault("SCRAPER_FIELD_TEMPERATURE", "temperature"),
+		Humidity:    envDefault("SCRAPER_FIELD_HUMIDITY", "humidity"),
+		Timestamp:   envDefault("SCRAPER_FIELD_TIMESTAMP", "timestamp"),
+		Location:    envDefault("SCRAPER_FIELD_LOCATION", "location"),
+	}
+
+	return &HTTPJSONScraper{
+		ScraperName: name,
+		URL:         url,
+		Mapping:     mapping,
+		Every:       interval,
+		Client:      clientFromEnv(),
+	}, true
+}
+
+func envDefault(name, fallback string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return fallback
+}
@@ -0,0 +1,14 @@
+package scrapers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RegisterRoutes wires the scraper status endpoint onto router.
+func RegisterRoutes(router *gin.Engine, registry *Registry) {
+	router.GET("/scrapers/status", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"data": registry.Status()})
+	})
+}
@@ -0,0 +1,174 @@
+package scrapers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"brewing-temperature-monitor-app/internal/models"
+
+	"golang.org/x/net/proxy"
+)
+
+// FieldMapping names which keys in a scraped JSON payload map to the
+// SensorData fields.
+type FieldMapping struct {
+	Temperature string
+	Humidity    string
+	Timestamp   string
+	Location    string
+}
+
+// HTTPJSONScraper periodically GETs a JSON endpoint and maps its response
+// into a models.SensorData reading using Mapping.
+type HTTPJSONScraper struct {
+	ScraperName string
+	URL         string
+	Mapping     FieldMapping
+	Every       time.Duration
+	Client      *http.Client
+}
+
+func (s *HTTPJSONScraper) Name() string            { return s.ScraperName }
+func (s *HTTPJSONScraper) Interval() time.Duration { return s.Every }
+
+func (s *HTTPJSONScraper) Fetch(ctx context.Context) ([]models.SensorData, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("scraper %q: unexpected status %d", s.ScraperName, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("scraper %q: failed to parse response: %w", s.ScraperName, err)
+	}
+
+	reading, err := s.mapReading(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return []models.SensorData{reading}, nil
+}
+
+func (s *HTTPJSONScraper) mapReading(raw map[string]interface{}) (models.SensorData, error) {
+	temperature, err := floatField(raw, s.Mapping.Temperature)
+	if err != nil {
+		return models.SensorData{}, fmt.Errorf("scraper %q: %w", s.ScraperName, err)
+	}
+
+	humidity, err := floatField(raw, s.Mapping.Humidity)
+	if err != nil {
+		return models.SensorData{}, fmt.Errorf("scraper %q: %w", s.ScraperName, err)
+	}
+
+	timestamp := time.Now().UTC()
+	if s.Mapping.Timestamp != "" {
+		if value, ok := raw[s.Mapping.Timestamp]; ok {
+			if parsed, err := parseTimestamp(value); err == nil {
+				timestamp = parsed
+			}
+		}
+	}
+
+	location := ""
+	if s.Mapping.Location != "" {
+		if value, ok := raw[s.Mapping.Location].(string); ok {
+			location = value
+		}
+	}
+
+	return models.SensorData{
+		DeviceID:         s.ScraperName,
+		Temperature:      temperature,
+		Humidity:         humidity,
+		Location:         location,
+		TimestampSampled: timestamp,
+	}, nil
+}
+
+func floatField(raw map[string]interface{}, key string) (float64, error) {
+	value, ok := raw[key]
+	if !ok {
+		return 0, fmt.Errorf("missing field %q", key)
+	}
+
+	switch v := value.(type) {
+	case float64:
+		return v, nil
+	case string:
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return 0, fmt.Errorf("field %q is not numeric: %w", key, err)
+		}
+		return parsed, nil
+	default:
+		return 0, fmt.Errorf("field %q has unsupported type %T", key, value)
+	}
+}
+
+func parseTimestamp(raw interface{}) (time.Time, error) {
+	switch v := raw.(type) {
+	case string:
+		return time.Parse(time.RFC3339, v)
+	case float64:
+		return time.Unix(int64(v), 0).UTC(), nil
+	default:
+		return time.Time{}, fmt.Errorf("unsupported timestamp type %T", raw)
+	}
+}
+
+// defaultScraperTimeout bounds how long a single Fetch request may take, so a
+// hung or slow feed can't block a scraper's goroutine (and its retry/backoff
+// loop) indefinitely.
+const defaultScraperTimeout = 10 * time.Second
+
+// clientFromEnv builds an http.Client that dials through HTTP_PROXY (or any
+// proxy scheme golang.org/x/net/proxy understands, including SOCKS5), so
+// scrapers keep working behind restrictive networks. Its Timeout is
+// configurable via SCRAPER_HTTP_TIMEOUT, defaulting to defaultScraperTimeout.
+func clientFromEnv() *http.Client {
+	dialer := proxy.FromEnvironment()
+
+	transport := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			return dialer.Dial(network, addr)
+		},
+	}
+
+	timeout := defaultScraperTimeout
+	if raw := os.Getenv("SCRAPER_HTTP_TIMEOUT"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil {
+			timeout = parsed
+		}
+	}
+
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
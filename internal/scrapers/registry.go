@@ -0,0 +1,149 @@
+package scrapers
+
+import (
+	"context"
+	"log"
+	"math"
+	"sync"
+	"time"
+
+	"brewing-temperature-monitor-app/internal/models"
+
+	influxdb2 "github.com/influxdata/influxdb-client-go/v2"
+	"github.com/influxdata/influxdb-client-go/v2/api"
+)
+
+// Status reports a scraper's last successful and last failed fetch, for
+// exposing at /scrapers/status.
+type Status struct {
+	Name        string    `json:"name"`
+	LastSuccess time.Time `json:"lastSuccess,omitempty"`
+	LastError   string    `json:"lastError,omitempty"`
+	LastErrorAt time.Time `json:"lastErrorAt,omitempty"`
+}
+
+// Registry runs a set of Scrapers on their own intervals and writes their
+// readings through the shared batch writer, tagging each point with a
+// "source" tag so scraped data can be told apart from POSTed device data.
+type Registry struct {
+	writeAPI api.WriteAPI
+
+	mu       sync.RWMutex
+	statuses map[string]*Status
+}
+
+// NewRegistry builds a Registry that writes scraped points through
+// writeAPI, the same async WriteAPI used by the HTTP handlers.
+func NewRegistry(writeAPI api.WriteAPI) *Registry {
+	return &Registry{
+		writeAPI: writeAPI,
+		statuses: make(map[string]*Status),
+	}
+}
+
+// Start launches one goroutine per scraper that fetches on its own interval
+// until ctx is cancelled.
+func (r *Registry) Start(ctx context.Context, scraperList ...Scraper) {
+	for _, scraper := range scraperList {
+		r.mu.Lock()
+		r.statuses[scraper.Name()] = &Status{Name: scraper.Name()}
+		r.mu.Unlock()
+
+		go r.run(ctx, scraper)
+	}
+}
+
+// Status returns a snapshot of every registered scraper's last success/error.
+func (r *Registry) Status() []Status {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make([]Status, 0, len(r.statuses))
+	for _, status := range r.statuses {
+		statuses = append(statuses, *status)
+	}
+	return statuses
+}
+
+func (r *Registry) run(ctx context.Context, scraper Scraper) {
+	ticker := time.NewTicker(scraper.Interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			r.fetchWithRetry(ctx, scraper)
+		}
+	}
+}
+
+const (
+	scraperMaxRetries = 3
+	scraperMaxBackoff = 30 * time.Second
+)
+
+// fetchWithRetry retries a failed fetch with exponential backoff (1s, 2s,
+// 4s, ... capped at scraperMaxBackoff) before recording the failure and
+// giving up until the next tick.
+func (r *Registry) fetchWithRetry(ctx context.Context, scraper Scraper) {
+	readings, err := fetchWithBackoff(ctx, scraper)
+
+	r.mu.Lock()
+	status := r.statuses[scraper.Name()]
+	if err != nil {
+		status.LastError = err.Error()
+		status.LastErrorAt = time.Now().UTC()
+	} else {
+		status.LastSuccess = time.Now().UTC()
+	}
+	r.mu.Unlock()
+
+	if err != nil {
+		log.Printf("scraper %q failed after retries: %v", scraper.Name(), err)
+		return
+	}
+
+	for _, reading := range readings {
+		point := influxdb2.NewPointWithMeasurement("sensor_data").
+			AddTag("device_id", reading.DeviceID).
+			AddTag("source", scraper.Name()).
+			AddField("temperature", reading.Temperature).
+			AddField("humidity", reading.Humidity).
+			AddField("location", reading.Location).
+			SetTime(reading.TimestampSampled)
+
+		r.writeAPI.WritePoint(point)
+	}
+}
+
+// fetchWithBackoff calls scraper.Fetch, retrying failures with exponential
+// backoff up to scraperMaxRetries times.
+func fetchWithBackoff(ctx context.Context, scraper Scraper) ([]models.SensorData, error) {
+	var readings []models.SensorData
+	var err error
+
+	for attempt := 0; attempt <= scraperMaxRetries; attempt++ {
+		readings, err = scraper.Fetch(ctx)
+		if err == nil {
+			return readings, nil
+		}
+		if attempt == scraperMaxRetries {
+			break
+		}
+
+		backoff := time.Duration(math.Pow(2, float64(attempt))) * time.Second
+		if backoff > scraperMaxBackoff {
+			backoff = scraperMaxBackoff
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+
+	return nil, err
+}
@@ -0,0 +1,18 @@
+// Package scrapers pulls sensor readings from external public feeds (e.g.
+// third-party air-quality/weather APIs) and forwards them into InfluxDB
+// alongside the data devices POST directly.
+package scrapers
+
+import (
+	"context"
+	"time"
+
+	"brewing-temperature-monitor-app/internal/models"
+)
+
+// Scraper periodically fetches sensor readings from an external source.
+type Scraper interface {
+	Name() string
+	Fetch(ctx context.Context) ([]models.SensorData, error)
+	Interval() time.Duration
+}